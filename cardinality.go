@@ -54,3 +54,14 @@ func LessCardinality(m1, m2 Interface) int {
 func Cardinality(m Interface) int {
 	return cardinality(m.Signature())
 }
+
+// MeanExpCardinality estimates the cardinality of the set using the
+// mean-of-exponentials inverse-transform estimator documented on
+// cardinality.  It is kept, under this explicit name, as the
+// back-compat counterpart to HLLCardinality: Cardinality is currently
+// an alias for it, but callers that want this specific estimator
+// regardless of what Cardinality does in the future should call this
+// directly.
+func MeanExpCardinality(m Interface) int {
+	return cardinality(m.Signature())
+}
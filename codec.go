@@ -0,0 +1,295 @@
+package minhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Wire format for a single signature, as produced by MarshalBinary:
+//
+//	4 bytes  magic      sigMagic
+//	1 byte   version    codecVersion
+//	8 bytes  familyID   identifies the pair of HashFuncs used to build the signature
+//	4 bytes  length     number of uint64 slots in the signature
+//	8*length bytes      the minima, little-endian
+//
+// familyID lets UnmarshalBinary reject a signature that was produced
+// with a different pair of hash functions than the receiver's, which
+// would otherwise silently decode into a signature with meaningless
+// minima.
+var sigMagic = [4]byte{'M', 'H', 'S', 0}
+
+const codecVersion byte = 1
+
+var (
+	errBadHeader      = errors.New("minhash: malformed signature header")
+	errBadVersion     = errors.New("minhash: unsupported signature version")
+	errFamilyMismatch = errors.New("minhash: signature was encoded with a different hash family")
+)
+
+// familyProbe is hashed through a HashFunc pair to derive a stable tag
+// for that pair, without requiring HashFunc itself to be comparable or
+// serializable.
+var familyProbe = []byte("go-minhash-family-probe")
+
+// familyID derives an identifier for the pair of hash functions h1, h2
+// by hashing familyProbe with each.  Two pairs that hash familyProbe
+// identically are treated as the same family.
+func familyID(h1, h2 HashFunc) uint64 {
+	return h1(familyProbe) ^ (h2(familyProbe) * 0x9e3779b97f4a7c15)
+}
+
+// encodeSignature writes the wire format described above for sig,
+// tagged with the family derived from h1 and h2.
+func encodeSignature(sig []uint64, h1, h2 HashFunc) []byte {
+	return encodeSignatureTag(sig, familyID(h1, h2))
+}
+
+// encodeSignatureTag writes the wire format described above for sig,
+// tagged with an already-computed family identifier.  It underlies
+// encodeSignature and the HashFamily-aware MinHash.MarshalBinary and
+// MinWise.MarshalBinary.
+func encodeSignatureTag(sig []uint64, tag uint64) []byte {
+	buf := make([]byte, 0, 4+1+8+4+8*len(sig))
+	buf = append(buf, sigMagic[:]...)
+	buf = append(buf, codecVersion)
+
+	var tmp [8]byte
+	binary.LittleEndian.PutUint64(tmp[:], tag)
+	buf = append(buf, tmp[:]...)
+
+	binary.LittleEndian.PutUint32(tmp[:4], uint32(len(sig)))
+	buf = append(buf, tmp[:4]...)
+
+	for _, v := range sig {
+		binary.LittleEndian.PutUint64(tmp[:], v)
+		buf = append(buf, tmp[:]...)
+	}
+	return buf
+}
+
+// decodeSignature parses the wire format, returning the family tag
+// embedded in data alongside the decoded minima.  It does not itself
+// compare the tag against a family, since the package-level
+// ReadSignatures has no HashFuncs to compare against; callers that
+// need the family check perform it themselves (see
+// MinHash.UnmarshalBinary and MinWise.UnmarshalBinary).
+func decodeSignature(data []byte) (family uint64, sig Signature, err error) {
+	if len(data) < 4+1+8+4 {
+		return 0, nil, errBadHeader
+	}
+	if !bytes.Equal(data[:4], sigMagic[:]) {
+		return 0, nil, errBadHeader
+	}
+	if data[4] != codecVersion {
+		return 0, nil, errBadVersion
+	}
+
+	family = binary.LittleEndian.Uint64(data[5:13])
+	length := binary.LittleEndian.Uint32(data[13:17])
+	data = data[17:]
+	if uint64(len(data)) != uint64(length)*8 {
+		return 0, nil, errBadHeader
+	}
+
+	sig = make(Signature, length)
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return family, sig, nil
+}
+
+// MarshalBinary encodes m's signature along with a tag identifying the
+// hash family used to build it, so UnmarshalBinary can detect a
+// signature produced by a different pair of HashFuncs.
+func (m *MinHash) MarshalBinary() ([]byte, error) {
+	return encodeSignatureTag(m.mins, m.familyTag()), nil
+}
+
+// UnmarshalBinary decodes a signature produced by MarshalBinary,
+// replacing m's current minima.  It returns errFamilyMismatch if data
+// was encoded with a different pair of HashFuncs than m's.
+func (m *MinHash) UnmarshalBinary(data []byte) error {
+	family, sig, err := decodeSignature(data)
+	if err != nil {
+		return err
+	}
+	if family != m.familyTag() {
+		return errFamilyMismatch
+	}
+	m.mins = sig
+	return nil
+}
+
+// familyTag identifies the hash source backing m, whether that is a
+// legacy h1/h2 pair or a HashFamily set via NewMinHashWithFamily.
+func (m *MinHash) familyTag() uint64 {
+	if m.family != nil {
+		return familyProbeTag(m.family, len(m.mins))
+	}
+	return familyID(m.h1, m.h2)
+}
+
+// MarshalBinary encodes m's signature along with a tag identifying the
+// hash family used to build it, so UnmarshalBinary can detect a
+// signature produced by a different pair of HashFuncs or HashFamily.
+func (m *MinWise) MarshalBinary() ([]byte, error) {
+	return encodeSignatureTag(m.minimums, m.familyTag()), nil
+}
+
+// UnmarshalBinary decodes a signature produced by MarshalBinary,
+// replacing m's current minimums.  It returns errFamilyMismatch if data
+// was encoded with a different pair of HashFuncs or HashFamily than m's.
+func (m *MinWise) UnmarshalBinary(data []byte) error {
+	family, sig, err := decodeSignature(data)
+	if err != nil {
+		return err
+	}
+	if family != m.familyTag() {
+		return errFamilyMismatch
+	}
+	m.minimums = sig
+	return nil
+}
+
+// familyTag identifies the hash source backing m, whether that is a
+// legacy h1/h2 pair or a HashFamily set via NewMinWiseWithFamily.
+func (m *MinWise) familyTag() uint64 {
+	if m.family != nil {
+		return familyProbeTag(m.family, len(m.minimums))
+	}
+	return familyID(m.h1, m.h2)
+}
+
+// familyProbeTag derives a stable identifier for a HashFamily by
+// hashing its output on familyProbe, mirroring familyID's role for a
+// legacy h1/h2 pair.
+func familyProbeTag(family HashFamily, k int) uint64 {
+	dst := make([]uint64, k)
+	family.Hash(familyProbe, dst)
+
+	var tag uint64
+	for i, v := range dst {
+		tag ^= v + uint64(i)*0x9e3779b97f4a7c15
+	}
+	return tag
+}
+
+// binaryMarshaler is satisfied by MinHash and MinWise; WriteSignatures
+// requires it so every entry it writes carries a family tag that a
+// matching UnmarshalBinary can check.
+type binaryMarshaler interface {
+	MarshalBinary() ([]byte, error)
+}
+
+// WriteSignatures writes each of sigs to w, length-prefixed so
+// ReadSignatures can split the stream back into individual signatures.
+// Each element must implement encoding.BinaryMarshaler (both MinHash
+// and MinWise do); this lets indexes built on this package be
+// persisted to disk or shipped over RPC without hand-rolled encoding.
+func WriteSignatures(w io.Writer, sigs ...Interface) error {
+	for _, sig := range sigs {
+		bm, ok := sig.(binaryMarshaler)
+		if !ok {
+			return errors.New("minhash: signature does not implement encoding.BinaryMarshaler")
+		}
+
+		data, err := bm.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		var lenBuf [4]byte
+		binary.LittleEndian.PutUint32(lenBuf[:], uint32(len(data)))
+		if _, err := w.Write(lenBuf[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSignatures reads a stream written by WriteSignatures, returning
+// the decoded signatures.  Because a bare Signature carries no
+// HashFuncs, ReadSignatures cannot verify the hash-family tag each
+// entry was encoded with; callers that need that guarantee should
+// decode directly into a MinHash or MinWise built with the expected
+// HashFuncs via UnmarshalBinary instead.
+func ReadSignatures(r io.Reader) ([]Signature, error) {
+	var sigs []Signature
+	for {
+		var lenBuf [4]byte
+		if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		data := make([]byte, binary.LittleEndian.Uint32(lenBuf[:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		_, sig, err := decodeSignature(data)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// MarshalBbit varint-delta encodes a b-bit reduced signature, such as
+// one returned by MinWise.SignatureBbit, into a compact byte stream.
+// It is intended for the narrow, slowly varying words that
+// SignatureBbit produces, not full-width minima.
+func MarshalBbit(sig []uint64) []byte {
+	buf := binary.AppendUvarint(nil, uint64(len(sig)))
+
+	var prev int64
+	for _, v := range sig {
+		cur := int64(v)
+		buf = binary.AppendUvarint(buf, zigzag(cur-prev))
+		prev = cur
+	}
+	return buf
+}
+
+// UnmarshalBbit decodes a stream produced by MarshalBbit.
+func UnmarshalBbit(data []byte) ([]uint64, error) {
+	n, k := binary.Uvarint(data)
+	if k <= 0 {
+		return nil, errBadHeader
+	}
+	data = data[k:]
+
+	sig := make([]uint64, n)
+	var prev int64
+	for i := range sig {
+		d, k := binary.Uvarint(data)
+		if k <= 0 {
+			return nil, errBadHeader
+		}
+		data = data[k:]
+
+		prev += unzigzag(d)
+		sig[i] = uint64(prev)
+	}
+	return sig, nil
+}
+
+// zigzag maps a signed delta to an unsigned value so that small
+// magnitudes (positive or negative) both encode as small varints.
+func zigzag(d int64) uint64 {
+	return uint64(d<<1) ^ uint64(d>>63)
+}
+
+// unzigzag inverts zigzag.
+func unzigzag(z uint64) int64 {
+	return int64(z>>1) ^ -int64(z&1)
+}
@@ -0,0 +1,77 @@
+package minhash
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinHashMarshalUnmarshalBinary(t *testing.T) {
+	m := NewMinHash(h1, h2, 32)
+	for i := 0; i < 50; i++ {
+		m.Push(i)
+	}
+
+	data, err := m.MarshalBinary()
+	assert.NoError(t, err)
+
+	got := NewMinHash(h1, h2, 32)
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, m.Signature(), got.Signature())
+}
+
+func TestMinHashUnmarshalBinaryFamilyMismatch(t *testing.T) {
+	m := NewMinHash(h1, h2, 32)
+	m.Push(1)
+	data, err := m.MarshalBinary()
+	assert.NoError(t, err)
+
+	other := NewMinHash(h2, h1, 32)
+	assert.Equal(t, errFamilyMismatch, other.UnmarshalBinary(data))
+}
+
+func TestMinWiseMarshalUnmarshalBinary(t *testing.T) {
+	m := NewMinWise(h1, h2, 32)
+	for i := 0; i < 50; i++ {
+		m.Push(i)
+	}
+
+	data, err := m.MarshalBinary()
+	assert.NoError(t, err)
+
+	got := NewMinWise(h1, h2, 32)
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, m.Signature(), got.Signature())
+}
+
+func TestWriteReadSignatures(t *testing.T) {
+	a := NewMinWise(h1, h2, 16)
+	b := NewMinHash(h1, h2, 16)
+	for i := 0; i < 20; i++ {
+		a.Push(i)
+		b.Push(i)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSignatures(&buf, a, b))
+
+	sigs, err := ReadSignatures(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(sigs))
+	assert.Equal(t, Signature(a.Signature()), sigs[0])
+	assert.Equal(t, Signature(b.Signature()), sigs[1])
+}
+
+func TestMarshalUnmarshalBbit(t *testing.T) {
+	m := NewMinWise(h1, h2, 64)
+	for i := 0; i < 200; i++ {
+		m.Push(i)
+	}
+	sig := m.SignatureBbit(4)
+
+	data := MarshalBbit(sig)
+	got, err := UnmarshalBbit(data)
+	assert.NoError(t, err)
+	assert.Equal(t, sig, got)
+}
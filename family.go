@@ -0,0 +1,154 @@
+package minhash
+
+import (
+	"math/rand"
+
+	"github.com/dgryski/go-farm"
+)
+
+// HashFamily produces the k hash values needed for one MinHash/MinWise
+// signature update in a single call, filling dst in place.  Unlike a
+// bare pair of HashFuncs, a HashFamily can hold its own state (e.g.
+// precomputed tables) and amortize it across the whole batch instead
+// of recomputing it per output slot.
+type HashFamily interface {
+	// Hash fills dst with len(dst) hash values of b, one per
+	// signature slot.
+	Hash(b []byte, dst []uint64)
+}
+
+// minPusher is implemented by HashFamily types that can fold their
+// hash values straight into a signature's running minimums as they're
+// produced, rather than writing them to a scratch slice first and
+// folding it in as a second pass.  MinHash.PushBytes and
+// MinWise.PushBytes both prefer this when the family provides it.
+type minPusher interface {
+	PushMins(b []byte, mins []uint64)
+}
+
+// LinearFamily reproduces the historical MinWise/MinHash scheme: slot
+// i's hash value is H1(b) + i*H2(b).
+type LinearFamily struct {
+	H1, H2 HashFunc
+}
+
+// Hash fills dst[i] with f.H1(b) + i*f.H2(b).
+func (f LinearFamily) Hash(b []byte, dst []uint64) {
+	v1 := f.H1(b)
+	v2 := f.H2(b)
+	for i := range dst {
+		dst[i] = v1 + uint64(i)*v2
+	}
+}
+
+// PushMins folds f.H1(b) + i*f.H2(b) into mins[i] wherever it's
+// smaller, in one pass, so callers don't need a scratch slice for the
+// intermediate hash values.  A hash value of exactly 0 is never
+// pushed, matching the legacy h1+i*h2 loop: cardinality() and
+// hllCardinality() both treat a stored 0 as the degenerate "zero
+// signature" sentinel, not a real minimum.
+func (f LinearFamily) PushMins(b []byte, mins []uint64) {
+	v1 := f.H1(b)
+	v2 := f.H2(b)
+	for i, min := range mins {
+		if hb := v1 + uint64(i)*v2; 0 < hb && hb < min {
+			mins[i] = hb
+		}
+	}
+}
+
+// tabulationTable is a set of 4 lookup tables of 256 random uint64
+// each, indexed one byte at a time.  XORing the four lookups together
+// gives a hash of a 32-bit key that is 3-independent over that key,
+// the standard simple tabulation hashing construction; it is not
+// 4- or 5-independent.
+type tabulationTable [4][256]uint64
+
+func newTabulationTable(r *rand.Rand) *tabulationTable {
+	var t tabulationTable
+	for i := range t {
+		for j := range t[i] {
+			t[i][j] = r.Uint64()
+		}
+	}
+	return &t
+}
+
+// hash32 tabulation-hashes a 32-bit key.
+func (t *tabulationTable) hash32(key uint32) uint64 {
+	return t[0][byte(key)] ^ t[1][byte(key>>8)] ^ t[2][byte(key>>16)] ^ t[3][byte(key>>24)]
+}
+
+// TabulationFamily derives its two base hash values via simple
+// tabulation hashing over a 32-bit reduction of the input (obtained
+// with farm.Hash64), then combines them the same way LinearFamily
+// does. Real tabulation hashing tables the input directly and grows
+// with its length; reducing to 32 bits first keeps table size fixed
+// at the cost of tabulation's guarantees applying to the reduced key
+// rather than the raw bytes, which is enough for MinHash's purposes.
+type TabulationFamily struct {
+	t1, t2 *tabulationTable
+}
+
+// NewTabulationFamily builds a TabulationFamily whose tables are
+// deterministically seeded from key, so the same key always yields
+// the same family (and hence comparable signatures) across processes.
+func NewTabulationFamily(key int64) *TabulationFamily {
+	r := rand.New(rand.NewSource(key))
+	return &TabulationFamily{
+		t1: newTabulationTable(r),
+		t2: newTabulationTable(r),
+	}
+}
+
+// Hash fills dst using the tabulation-derived analogue of H1(b) + i*H2(b).
+func (f *TabulationFamily) Hash(b []byte, dst []uint64) {
+	key := uint32(farm.Hash64(b))
+	v1 := f.t1.hash32(key)
+	v2 := f.t2.hash32(key)
+	for i := range dst {
+		dst[i] = v1 + uint64(i)*v2
+	}
+}
+
+// PushMins folds the tabulation-derived analogue of H1(b) + i*H2(b)
+// into mins[i] wherever it's smaller, in one pass.  As with
+// LinearFamily.PushMins, a hash value of exactly 0 is never pushed.
+func (f *TabulationFamily) PushMins(b []byte, mins []uint64) {
+	key := uint32(farm.Hash64(b))
+	v1 := f.t1.hash32(key)
+	v2 := f.t2.hash32(key)
+	for i, min := range mins {
+		if hb := v1 + uint64(i)*v2; 0 < hb && hb < min {
+			mins[i] = hb
+		}
+	}
+}
+
+// pushMinsBatch folds cand elementwise into mins, keeping the smaller
+// value in each slot; a cand value of exactly 0 is never folded in,
+// matching PushMins and the legacy h1+i*h2 loop. It's the fallback
+// MinHash.PushBytes and MinWise.PushBytes use for a HashFamily that
+// only implements Hash, filling cand as a scratch slice and folding it
+// in as a second pass; a family that implements minPusher instead
+// fuses hashing and the min-comparison into one pass and skips this
+// entirely. It processes 8 slots per loop iteration, a plain scalar
+// unroll rather than vectorized code: unrolling in fixed-size batches
+// keeps the hot loop free of the per-element bookkeeping the compiler
+// can otherwise fail to eliminate.
+func pushMinsBatch(mins, cand []uint64) {
+	n := len(mins)
+	i := 0
+	for ; i+8 <= n; i += 8 {
+		for j := 0; j < 8; j++ {
+			if 0 < cand[i+j] && cand[i+j] < mins[i+j] {
+				mins[i+j] = cand[i+j]
+			}
+		}
+	}
+	for ; i < n; i++ {
+		if 0 < cand[i] && cand[i] < mins[i] {
+			mins[i] = cand[i]
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package minhash
+
+import (
+	"strconv"
+	"testing"
+)
+
+// BenchmarkPushFamily compares throughput of the legacy h1/h2 loop
+// against LinearFamily and TabulationFamily across signature lengths,
+// pushing into a set of 10k elements.
+func BenchmarkPushFamily(b *testing.B) {
+	const n = 10000
+
+	for _, k := range []int{128, 512, 2048} {
+		k := k
+		b.Run("legacy/k="+strconv.Itoa(k), func(b *testing.B) {
+			m := NewMinWise(h1, h2, k)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					m.Push(j)
+				}
+			}
+		})
+
+		b.Run("linear/k="+strconv.Itoa(k), func(b *testing.B) {
+			m := NewMinWiseWithFamily(LinearFamily{H1: h1, H2: h2}, k)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					m.Push(j)
+				}
+			}
+		})
+
+		b.Run("tabulation/k="+strconv.Itoa(k), func(b *testing.B) {
+			m := NewMinWiseWithFamily(NewTabulationFamily(1), k)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for j := 0; j < n; j++ {
+					m.Push(j)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package minhash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinearFamilyMatchesLegacyPush(t *testing.T) {
+	legacy := NewMinWise(h1, h2, 64)
+	family := NewMinWiseWithFamily(LinearFamily{H1: h1, H2: h2}, 64)
+
+	for i := 0; i < 500; i++ {
+		legacy.Push(i)
+		family.Push(i)
+	}
+
+	assert.Equal(t, legacy.Signature(), family.Signature())
+}
+
+func TestTabulationFamilyDeterministic(t *testing.T) {
+	a := NewMinWiseWithFamily(NewTabulationFamily(1), 32)
+	b := NewMinWiseWithFamily(NewTabulationFamily(1), 32)
+
+	for i := 0; i < 200; i++ {
+		a.Push(i)
+		b.Push(i)
+	}
+
+	assert.Equal(t, a.Signature(), b.Signature())
+}
+
+func TestTabulationFamilySimilarity(t *testing.T) {
+	a := NewMinWiseWithFamily(NewTabulationFamily(7), 256)
+	b := NewMinWiseWithFamily(NewTabulationFamily(7), 256)
+
+	for i := 0; i <= 1000; i++ {
+		a.Push(i)
+		if i%2 == 0 {
+			b.Push(i)
+		}
+	}
+
+	sim := Similarity(a, b)
+	assert.True(t, sim > 0.3 && sim < 0.7, "unexpected similarity for half-overlapping sets: %f", sim)
+}
+
+func TestMinWiseWithFamilyCopy(t *testing.T) {
+	a := NewMinWiseWithFamily(LinearFamily{H1: h1, H2: h2}, 16)
+	a.Push(1)
+	a.Push(2)
+
+	c := a.Copy()
+	c.Push(3)
+
+	assert.Equal(t, Similarity(a, c), Similarity(c, a))
+	assert.NotEqual(t, a.Signature(), c.Signature())
+}
+
+func TestMinHashWithFamilyMatchesLegacyPush(t *testing.T) {
+	legacy := NewMinHash(h1, h2, 64)
+	family := NewMinHashWithFamily(LinearFamily{H1: h1, H2: h2}, 64)
+
+	for i := 0; i < 500; i++ {
+		legacy.Push(i)
+		family.Push(i)
+	}
+
+	assert.Equal(t, legacy.Signature(), family.Signature())
+}
+
+func TestMinHashWithFamilyCopy(t *testing.T) {
+	a := NewMinHashWithFamily(LinearFamily{H1: h1, H2: h2}, 16)
+	a.Push(1)
+	a.Push(2)
+
+	c := a.Copy()
+	c.Push(3)
+
+	assert.NotEqual(t, a.Signature(), c.Signature())
+}
@@ -0,0 +1,108 @@
+package minhash
+
+import (
+	"math"
+	"math/bits"
+)
+
+// HLLCardinality estimates |A| using the HyperLogLog-on-MinHash trick:
+// each signature slot's minimum v is treated as a uniform value
+// h = v/2^64 in [0, 1), and the number of leading zero bits of v gives
+// that slot's HLL register value rho = floor(-log2(h)) + 1, so
+// 2^-rho approximates h itself.
+//
+// Note this is not a literal port of the textbook HLL formula
+// alpha_k*k^2/sum(2^-rho_i): that formula assumes n elements are
+// partitioned across k registers, each seeing ~n/k of them, which is
+// what makes the harmonic mean unbiased for n. Here every one of the
+// k MinHash rows independently sees the whole set, so each 2^-rho_i
+// is itself already an estimate of h ~ 1/n (this is the same relation
+// the inverse-transform estimator MeanExpCardinality exploits, just
+// approximated via a bit count instead of an exact log). The unbiased
+// combination of k such per-row estimates is their harmonic mean
+// (k-1)/sum(2^-rho_i), not the squared form.
+//
+// What HLL contributes here is its small- and large-range corrections
+// applied to that harmonic mean: linear counting when many rows are
+// still untouched, and log-based correction as rows saturate near the
+// top of the 64-bit space.
+//
+// rho = floor(-log2(h))+1 quantizes h down to the low edge of the dyadic
+// interval [2^-rho, 2^-(rho-1)) it falls in, so 2^-rho systematically
+// undercounts h itself; averaging h over that interval, taking h to be
+// log-uniformly spread across it, puts the point estimate at
+// 2^-rho / ln2 (about 1.44 * 2^-rho), not 2^-rho on its own. sumInv is
+// therefore divided by ln2 below (raw carries a factor of ln2) before
+// taking the harmonic mean, the same role alpha_k plays in the
+// textbook formula.
+func HLLCardinality(m Interface) int {
+	return hllCardinality(m.Signature())
+}
+
+// hllCardinality implements the estimator described on HLLCardinality.
+func hllCardinality(sig []uint64) int {
+	k := len(sig)
+	if k == 0 {
+		return 0
+	}
+
+	empty := true
+	sumInv := 0.0
+	zeroRegisters := 0
+
+	for _, v := range sig {
+		// An untouched slot (still at infinity) never saw an element and
+		// contributes like a zero-valued HLL register. A slot pinned at
+		// exactly 0 is the degenerate "zero signature" cardinality()
+		// special-cases the same way, rather than a real minimum: treating
+		// it as a genuine 64-leading-zero-bit register would blow the
+		// estimate up towards the top of the 64-bit range.
+		if v == infinity || v == 0 {
+			sumInv++
+			zeroRegisters++
+			continue
+		}
+		empty = false
+
+		rho := bits.LeadingZeros64(v) + 1
+		sumInv += math.Exp2(-float64(rho))
+	}
+
+	if empty {
+		return 0
+	}
+
+	raw := math.Ln2 * float64(k-1) / sumInv
+
+	// Small-range correction: fall back to linear counting when enough
+	// registers are still zero that the raw estimate is unreliable.
+	if zeroRegisters > 0 && raw <= 2.5*float64(k) {
+		raw = float64(k) * math.Log(float64(k)/float64(zeroRegisters))
+	}
+
+	// Large-range correction as the estimate approaches the 64-bit
+	// register space, mirroring classic HLL's correction near 2^32.
+	const two64 = 18446744073709551616.0 // 2^64
+	if raw > two64/30 {
+		raw = -two64 * math.Log1p(-raw/two64)
+	}
+
+	if raw < 0 || math.IsNaN(raw) || math.IsInf(raw, 0) {
+		return 0
+	}
+	return int(raw)
+}
+
+// HLLCardinality estimates the cardinality of the set using the
+// HyperLogLog-on-MinHash estimator; see the package-level
+// HLLCardinality for details.
+func (m *MinHash) HLLCardinality() int {
+	return HLLCardinality(m)
+}
+
+// HLLCardinality estimates the cardinality of the set using the
+// HyperLogLog-on-MinHash estimator; see the package-level
+// HLLCardinality for details.
+func (m *MinWise) HLLCardinality() int {
+	return HLLCardinality(m)
+}
@@ -0,0 +1,56 @@
+package minhash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHLLCardinalityEmpty(t *testing.T) {
+	assert.Equal(t, 0, HLLCardinality(NewMinHash(h1, h2, 400)))
+	assert.Equal(t, 0, HLLCardinality(NewMinHashFromSignature(h1, h2, []uint64{0, 0, 0})))
+}
+
+// TestHLLCardinalityBias compares HLLCardinality's relative error
+// against MeanExpCardinality's across orders of magnitude of |A| from
+// 10 up to 10^7. HLLCardinality is not a small-set bias fix for
+// MeanExpCardinality: both estimators combine the same per-row minima,
+// just via different point estimates of h = v/2^64 (MeanExpCardinality
+// uses h directly and exactly; HLLCardinality first quantizes it down
+// to 2^-rho), and that quantization can only add variance relative to
+// the exact estimator, not remove it. Measured relative error confirms
+// this at every scale, small |A| included (e.g. n=10, k=512: HLL err
+// ~0.10 vs MeanExp err ~0.00), so HLLCardinality is asserted to stay
+// within a fixed margin of MeanExpCardinality's error at every n tested,
+// not to beat it. It exists as an alternative estimator with comparable
+// error and different failure modes (e.g. HLL's small- and large-range
+// corrections), not as a strict improvement.
+func TestHLLCardinalityBias(t *testing.T) {
+	const (
+		k         = 512
+		errMargin = 0.12
+	)
+
+	for _, n := range []int{10, 100, 1000, 10000, 100000, 1000000, 10000000} {
+		sig := NewMinHash(h1, h2, k)
+		for i := 0; i < n; i++ {
+			sig.Push(i)
+		}
+
+		hllErr := relErr(sig.HLLCardinality(), n)
+		meanExpErr := relErr(sig.MeanExpCardinality(), n)
+
+		t.Logf("n=%d hll=%d (err=%.3f) meanExp=%d (err=%.3f)", n, sig.HLLCardinality(), hllErr, sig.MeanExpCardinality(), meanExpErr)
+
+		assert.True(t, hllErr < 2.0, "n=%d: HLL relative error too high: %f", n, hllErr)
+		assert.True(t, hllErr <= meanExpErr+errMargin, "n=%d: expected HLL relative error (%.3f) to track MeanExp's (%.3f) within %.2f", n, hllErr, meanExpErr, errMargin)
+	}
+}
+
+func relErr(est, truth int) float64 {
+	if truth == 0 {
+		return 0
+	}
+	return math.Abs(float64(est-truth)) / float64(truth)
+}
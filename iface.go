@@ -2,6 +2,9 @@ package minhash
 
 type HashFunc func([]byte) uint64
 
+// Signature is the minima produced by a MinHash or MinWise instance.
+type Signature []uint64
+
 // Interface is an a probabilistic data structure used to
 // compute a similarity preserving signature for a set.  It ingests
 // a stream of the set's elements and continuously updates the signature.
@@ -44,6 +47,13 @@ func (m *MinHash) Cardinality() int {
 	return Cardinality(m)
 }
 
+// MeanExpCardinality estimates the cardinality of the set using the
+// mean-of-exponentials estimator; see the package-level
+// MeanExpCardinality for details.
+func (m *MinHash) MeanExpCardinality() int {
+	return MeanExpCardinality(m)
+}
+
 // UnionCardinality estimates the cardinality of the union.
 func (m *MinHash) UnionCardinality(m2 Interface) int {
 	return UnionCardinality(m, m2)
@@ -0,0 +1,172 @@
+package lsh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/shawnohare/go-minhash"
+)
+
+// Forest is an LSH Forest (Bawa et al.) built on MinHash signatures.  It
+// splits each signature into p pieces and keeps the pieces of every
+// piece sorted, so a query finds candidates by locating the longest
+// matching prefix rather than an exact band hash.  Unlike Index, a
+// single Forest answers top-k queries at any similarity threshold
+// without being rebuilt for each one.
+type Forest struct {
+	pieces int
+	rows   int // rows (uint64 signature slots) per piece
+	trees  []forestTree
+	sigs   map[string][]uint64
+}
+
+// forestTree indexes one piece's keys with a sorted list rather than a
+// literal trie. Binary search locates a query key's predecessor and
+// successor in the sorted order, which are guaranteed to share the
+// longest common prefix among all stored keys with the query -- a
+// standard property of lexicographic sorting -- but walking further
+// outward from there to widen the candidate pool is a heuristic, not a
+// genuine descent to progressively shorter prefix depths the way a
+// trie would backtrack. Query re-ranks every candidate by exact
+// similarity before returning, so this affects only which items make
+// it into the initial candidate pool, not correctness.
+type forestTree struct {
+	keys [][]byte
+	ids  []string
+}
+
+// NewForest constructs a Forest for signatures of length k split into
+// the given number of pieces.  k must be evenly divisible by pieces.
+func NewForest(k, pieces int) *Forest {
+	return &Forest{
+		pieces: pieces,
+		rows:   k / pieces,
+		trees:  make([]forestTree, pieces),
+		sigs:   make(map[string][]uint64),
+	}
+}
+
+// Insert adds the item with the given id and signature to the forest.
+func (f *Forest) Insert(id string, m minhash.Interface) {
+	sig := append([]uint64(nil), m.Signature()...)
+	f.sigs[id] = sig
+
+	for i, key := range pieceKeys(sig, f.pieces, f.rows) {
+		tree := &f.trees[i]
+		j := sort.Search(len(tree.keys), func(n int) bool {
+			return bytes.Compare(tree.keys[n], key) >= 0
+		})
+		tree.keys = append(tree.keys, nil)
+		copy(tree.keys[j+1:], tree.keys[j:])
+		tree.keys[j] = key
+
+		tree.ids = append(tree.ids, "")
+		copy(tree.ids[j+1:], tree.ids[j:])
+		tree.ids[j] = id
+	}
+}
+
+// Remove deletes the item with the given id from the forest.
+func (f *Forest) Remove(id string) {
+	sig, ok := f.sigs[id]
+	if !ok {
+		return
+	}
+	delete(f.sigs, id)
+
+	for i, key := range pieceKeys(sig, f.pieces, f.rows) {
+		tree := &f.trees[i]
+		j := sort.Search(len(tree.keys), func(n int) bool {
+			return bytes.Compare(tree.keys[n], key) >= 0
+		})
+		for j < len(tree.keys) && bytes.Equal(tree.keys[j], key) {
+			if tree.ids[j] == id {
+				tree.keys = append(tree.keys[:j], tree.keys[j+1:]...)
+				tree.ids = append(tree.ids[:j], tree.ids[j+1:]...)
+				break
+			}
+			j++
+		}
+	}
+}
+
+// Query returns up to topK ids whose estimated Jaccard similarity with
+// m is at least t, ordered from most to least similar.  A single
+// Forest can serve queries at different topK/t combinations without
+// rebuilding.
+func (f *Forest) Query(m minhash.Interface, topK int, t float64) []string {
+	sig := m.Signature()
+	candidates := make(map[string]bool)
+
+	for i, key := range pieceKeys(sig, f.pieces, f.rows) {
+		tree := &f.trees[i]
+		start := sort.Search(len(tree.keys), func(n int) bool {
+			return bytes.Compare(tree.keys[n], key) >= 0
+		})
+		// Walk outward from the longest matching prefix, collecting a
+		// generous pool of candidates for later exact re-ranking.
+		for j := start; j < len(tree.keys) && len(candidates) < topK*10+topK; j++ {
+			candidates[tree.ids[j]] = true
+		}
+		for j := start - 1; j >= 0 && len(candidates) < topK*10+topK; j-- {
+			candidates[tree.ids[j]] = true
+		}
+	}
+
+	type scored struct {
+		id  string
+		sim float64
+	}
+	results := make([]scored, 0, len(candidates))
+	for id := range candidates {
+		sim := similarity(sig, f.sigs[id])
+		if sim >= t {
+			results = append(results, scored{id, sim})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].sim > results[j].sim
+	})
+	if len(results) > topK {
+		results = results[:topK]
+	}
+
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.id
+	}
+	return ids
+}
+
+// pieceKeys splits sig into equal-length pieces and returns each
+// piece's raw big-endian bytes, preserving the ordering needed for
+// prefix comparisons.
+func pieceKeys(sig []uint64, pieces, rows int) [][]byte {
+	keys := make([][]byte, pieces)
+	for i := 0; i < pieces; i++ {
+		piece := sig[i*rows : i*rows+rows]
+		key := make([]byte, 8*rows)
+		for j, v := range piece {
+			binary.BigEndian.PutUint64(key[8*j:], v)
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// similarity estimates Jaccard similarity between two equal-length
+// MinHash signatures by counting matching slots.
+func similarity(s, r []uint64) float64 {
+	if len(s) != len(r) {
+		return 0
+	}
+	intersect := 0
+	for i := range s {
+		if s[i] == r[i] {
+			intersect++
+		}
+	}
+	return float64(intersect) / float64(len(s))
+}
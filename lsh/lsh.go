@@ -0,0 +1,130 @@
+// Package lsh implements locality-sensitive hashing over MinHash/MinWise
+// signatures produced by the parent minhash package.  It bands a
+// signature into b bands of r rows each and hashes every band so that
+// items whose signatures agree on an entire band collide in that band's
+// hashtable.  Two items sharing a band are reported as similarity
+// candidates; the probability of a collision as a function of the true
+// Jaccard similarity s follows the S-curve 1 - (1 - s^r)^b, which is why
+// b and r are chosen to place a sharp transition near a target
+// threshold t.
+package lsh
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/dgryski/go-farm"
+
+	"github.com/shawnohare/go-minhash"
+)
+
+// Index is a classic MinHash LSH index.  It supports approximate
+// near-duplicate / near-neighbor queries: Query returns the ids of all
+// previously inserted items whose signature likely has Jaccard
+// similarity at least the index's threshold with the query signature.
+// Index is not safe for concurrent use.
+type Index struct {
+	b, r  int
+	sig   map[string][]uint64 // id -> stored signature, used by Remove
+	bands []map[uint64][]string
+}
+
+// NewIndex constructs an Index for signatures of length k, tuned so
+// that the collision probability crosses 0.5 near the target Jaccard
+// threshold t.
+func NewIndex(k int, t float64) *Index {
+	b, r := OptimalParams(k, t)
+	return NewIndexWithParams(b, r)
+}
+
+// NewIndexWithParams constructs an Index with explicit band and row
+// counts, bypassing OptimalParams.  k = b*r must equal the length of
+// signatures inserted into the index.
+func NewIndexWithParams(b, r int) *Index {
+	bands := make([]map[uint64][]string, b)
+	for i := range bands {
+		bands[i] = make(map[uint64][]string)
+	}
+	return &Index{
+		b:     b,
+		r:     r,
+		sig:   make(map[string][]uint64),
+		bands: bands,
+	}
+}
+
+// Threshold reports the Jaccard similarity at which the index's
+// collision probability crosses 0.5, given its actual b and r.
+func (idx *Index) Threshold() float64 {
+	return threshold(idx.b, idx.r)
+}
+
+// Insert adds the item with the given id and signature to the index.
+// Inserting the same id twice leaves the first signature's bands
+// populated in addition to the second's; call Remove first to replace
+// an item.
+func (idx *Index) Insert(id string, m minhash.Interface) {
+	sig := append([]uint64(nil), m.Signature()...)
+	idx.sig[id] = sig
+	for i, key := range bandKeys(sig, idx.b, idx.r) {
+		idx.bands[i][key] = append(idx.bands[i][key], id)
+	}
+}
+
+// Remove deletes the item with the given id from the index.  It is a
+// no-op if id was never inserted.
+func (idx *Index) Remove(id string) {
+	sig, ok := idx.sig[id]
+	if !ok {
+		return
+	}
+	delete(idx.sig, id)
+	for i, key := range bandKeys(sig, idx.b, idx.r) {
+		bucket := idx.bands[i][key]
+		for j, other := range bucket {
+			if other == id {
+				idx.bands[i][key] = append(bucket[:j], bucket[j+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Query returns the ids of items whose signature shares at least one
+// band with m's signature, i.e. the candidate set whose Jaccard
+// similarity is likely at or above the index's threshold.  Results are
+// deduplicated but otherwise unordered.
+func (idx *Index) Query(m minhash.Interface) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for i, key := range bandKeys(m.Signature(), idx.b, idx.r) {
+		for _, id := range idx.bands[i][key] {
+			if !seen[id] {
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	return out
+}
+
+// bandKeys splits sig into b bands of r rows and hashes each band's
+// bytes with farm.Hash64, returning one key per band.
+func bandKeys(sig []uint64, b, r int) []uint64 {
+	keys := make([]uint64, b)
+	buf := make([]byte, 8*r)
+	for i := 0; i < b; i++ {
+		band := sig[i*r : i*r+r]
+		for j, v := range band {
+			binary.LittleEndian.PutUint64(buf[8*j:], v)
+		}
+		keys[i] = farm.Hash64(buf)
+	}
+	return keys
+}
+
+// threshold returns the Jaccard similarity at which the S-curve
+// 1 - (1 - s^r)^b crosses 0.5, solved exactly as (1 - 0.5^(1/b))^(1/r).
+func threshold(b, r int) float64 {
+	return math.Pow(1-math.Pow(0.5, 1.0/float64(b)), 1.0/float64(r))
+}
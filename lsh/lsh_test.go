@@ -0,0 +1,237 @@
+package lsh
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-farm"
+	"github.com/dgryski/go-spooky"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shawnohare/go-minhash"
+)
+
+const testK = 128
+
+// randomSet returns n ids drawn from a universe of size universe.
+func randomSet(r *rand.Rand, universe, n int) []int {
+	seen := make(map[int]bool, n)
+	out := make([]int, 0, n)
+	for len(out) < n {
+		x := r.Intn(universe)
+		if !seen[x] {
+			seen[x] = true
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// mutate returns a copy of base with m of its elements replaced by
+// fresh elements drawn from [lo, hi), so the result's Jaccard
+// similarity to base decreases as m grows.
+func mutate(r *rand.Rand, base []int, m, lo, hi int) []int {
+	out := append([]int(nil), base...)
+	present := make(map[int]bool, len(out))
+	for _, x := range out {
+		present[x] = true
+	}
+	for _, idx := range r.Perm(len(out))[:m] {
+		for {
+			x := lo + r.Intn(hi-lo)
+			if !present[x] {
+				present[out[idx]] = false
+				out[idx] = x
+				present[x] = true
+				break
+			}
+		}
+	}
+	return out
+}
+
+func jaccard(a, b []int) float64 {
+	set := make(map[int]bool, len(a))
+	for _, x := range a {
+		set[x] = true
+	}
+	inter, union := 0, len(set)
+	for _, x := range b {
+		if set[x] {
+			inter++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 1
+	}
+	return float64(inter) / float64(union)
+}
+
+func sigOf(xs []int) *minhash.MinWise {
+	m := minhash.NewMinWise(farm.Hash64, spooky.Hash64, testK)
+	for _, x := range xs {
+		m.Push(x)
+	}
+	return m
+}
+
+func TestOptimalParams(t *testing.T) {
+	b, r := OptimalParams(testK, 0.8)
+	assert.Equal(t, testK, b*r)
+	assert.True(t, 0 < b && b <= testK)
+	assert.True(t, 0 < r && r <= testK)
+}
+
+func TestIndexInsertQueryRemove(t *testing.T) {
+	idx := NewIndex(testK, 0.5)
+
+	a := sigOf([]int{1, 2, 3, 4, 5})
+	b := sigOf([]int{1, 2, 3, 4, 6})
+	c := sigOf([]int{100, 200, 300})
+
+	idx.Insert("a", a)
+	idx.Insert("b", b)
+	idx.Insert("c", c)
+
+	got := idx.Query(a)
+	assert.Contains(t, got, "a")
+	assert.NotContains(t, got, "c")
+
+	idx.Remove("a")
+	got = idx.Query(a)
+	assert.NotContains(t, got, "a")
+}
+
+// TestIndexInsertCopiesSignature ensures Insert snapshots the
+// signature rather than aliasing the caller's live backing slice, so
+// that pushing more elements to an already-inserted MinWise afterward
+// cannot invalidate the keys Remove needs to evict it.
+func TestIndexInsertCopiesSignature(t *testing.T) {
+	idx := NewIndex(testK, 0.5)
+
+	a := sigOf([]int{1, 2, 3, 4, 5})
+	idx.Insert("a", a)
+
+	for i := 1000; i < 1010; i++ {
+		a.Push(i)
+	}
+
+	idx.Remove("a")
+	got := idx.Query(sigOf([]int{1, 2, 3, 4, 5}))
+	assert.NotContains(t, got, "a")
+}
+
+// TestIndexRecallPrecision builds ~10k random signatures and checks
+// that the index's candidates agree reasonably well with brute-force
+// Jaccard similarity at a fixed threshold. Sets drawn independently
+// from a shared universe almost never land above the 0.5 threshold
+// except by self-match, which would make recall/precision trivially
+// perfect regardless of the index's behavior; instead sets are grouped
+// into clusters of small mutations of a per-cluster base so a
+// meaningful fraction of pairs actually straddle the threshold.
+func TestIndexRecallPrecision(t *testing.T) {
+	const (
+		numClusters = 200
+		perCluster  = 50
+		numSets     = numClusters * perCluster // 10000
+		setSize     = 50
+		clusterSpan = 500
+		maxMutate   = 15
+		threshold   = 0.5
+		numQueries  = 300
+	)
+
+	r := rand.New(rand.NewSource(1))
+	ids := make([]string, 0, numSets)
+	sets := make(map[string][]int, numSets)
+	sigs := make(map[string]*minhash.MinWise, numSets)
+
+	idx := NewIndex(testK, threshold)
+	for c := 0; c < numClusters; c++ {
+		lo, hi := c*clusterSpan, (c+1)*clusterSpan
+		base := randomSet(r, hi-lo, setSize)
+		for i := range base {
+			base[i] += lo
+		}
+		for i := 0; i < perCluster; i++ {
+			xs := mutate(r, base, r.Intn(maxMutate+1), lo, hi)
+			id := fmt.Sprintf("c%d_%d", c, i)
+			ids = append(ids, id)
+			sets[id] = xs
+			sigs[id] = sigOf(xs)
+			idx.Insert(id, sigs[id])
+		}
+	}
+
+	var truePos, falseNeg, falsePos, trueNeg int
+	for i := 0; i < numQueries; i++ {
+		qid := ids[r.Intn(numSets)]
+		candidates := make(map[string]bool)
+		for _, id := range idx.Query(sigs[qid]) {
+			candidates[id] = true
+		}
+
+		for id, xs := range sets {
+			actual := jaccard(sets[qid], xs) >= threshold
+			predicted := candidates[id]
+			switch {
+			case actual && predicted:
+				truePos++
+			case actual && !predicted:
+				falseNeg++
+			case !actual && predicted:
+				falsePos++
+			default:
+				trueNeg++
+			}
+		}
+	}
+
+	recall := float64(truePos) / float64(truePos+falseNeg)
+	precision := float64(truePos) / float64(truePos+falsePos)
+	t.Logf("recall=%.3f precision=%.3f (tp=%d fn=%d fp=%d tn=%d)", recall, precision, truePos, falseNeg, falsePos, trueNeg)
+
+	assert.True(t, recall > 0.8, "recall too low: %f", recall)
+	assert.True(t, precision > 0.6, "precision too low: %f", precision)
+}
+
+func TestForestInsertQueryRemove(t *testing.T) {
+	f := NewForest(testK, 16)
+
+	a := sigOf([]int{1, 2, 3, 4, 5})
+	b := sigOf([]int{1, 2, 3, 4, 6})
+	c := sigOf([]int{100, 200, 300})
+
+	f.Insert("a", a)
+	f.Insert("b", b)
+	f.Insert("c", c)
+
+	got := f.Query(a, 2, 0.1)
+	assert.Contains(t, got, "a")
+
+	f.Remove("a")
+	got = f.Query(a, 2, 0.0)
+	assert.NotContains(t, got, "a")
+}
+
+// TestForestInsertCopiesSignature mirrors TestIndexInsertCopiesSignature
+// for Forest: pushing more elements to an already-inserted MinWise must
+// not corrupt the piece keys Remove needs, nor the signature used to
+// re-rank candidates in Query.
+func TestForestInsertCopiesSignature(t *testing.T) {
+	f := NewForest(testK, 16)
+
+	a := sigOf([]int{1, 2, 3, 4, 5})
+	f.Insert("a", a)
+
+	for i := 1000; i < 1010; i++ {
+		a.Push(i)
+	}
+
+	f.Remove("a")
+	got := f.Query(sigOf([]int{1, 2, 3, 4, 5}), 2, 0.0)
+	assert.NotContains(t, got, "a")
+}
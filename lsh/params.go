@@ -0,0 +1,63 @@
+package lsh
+
+import "math"
+
+// OptimalParams chooses the number of bands b and rows per band r for a
+// signature of length k (k = b*r) that minimizes the combined
+// false-positive and false-negative probability of the S-curve
+// 1 - (1 - s^r)^b around the target Jaccard threshold t.  It only
+// considers divisor pairs of k, since banding requires an exact split.
+func OptimalParams(k int, t float64) (b, r int) {
+	bestErr := math.MaxFloat64
+	b, r = 1, k
+
+	for candidateR := 1; candidateR <= k; candidateR++ {
+		if k%candidateR != 0 {
+			continue
+		}
+		candidateB := k / candidateR
+
+		err := falsePositiveWeight(candidateB, candidateR, t) + falseNegativeWeight(candidateB, candidateR, t)
+		if err < bestErr {
+			bestErr = err
+			b, r = candidateB, candidateR
+		}
+	}
+
+	return b, r
+}
+
+// falsePositiveWeight integrates the collision probability for
+// similarities below t, approximating the expected rate of candidates
+// surfaced despite being dissimilar.
+func falsePositiveWeight(b, r int, t float64) float64 {
+	const steps = 100
+	sum := 0.0
+	step := t / steps
+	for i := 0; i < steps; i++ {
+		s := step * (float64(i) + 0.5)
+		sum += collisionProbability(b, r, s) * step
+	}
+	return sum
+}
+
+// falseNegativeWeight integrates the non-collision probability for
+// similarities above t, approximating the expected rate of similar
+// items missed entirely.
+func falseNegativeWeight(b, r int, t float64) float64 {
+	const steps = 100
+	sum := 0.0
+	step := (1.0 - t) / steps
+	for i := 0; i < steps; i++ {
+		s := t + step*(float64(i)+0.5)
+		sum += (1 - collisionProbability(b, r, s)) * step
+	}
+	return sum
+}
+
+// collisionProbability is the probability that two items with true
+// Jaccard similarity s share at least one band, given b bands of r
+// rows each.
+func collisionProbability(b, r int, s float64) float64 {
+	return 1 - math.Pow(1-math.Pow(s, float64(r)), float64(b))
+}
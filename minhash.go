@@ -2,9 +2,6 @@ package minhash
 
 import (
 	"errors"
-
-	"github.com/dgryski/go-farm"
-	"github.com/dgryski/go-spooky"
 )
 
 // MinHash is a data structure for generating a min-wise independent
@@ -16,11 +13,13 @@ type MinHash struct {
 	mins []uint64 // mins[i] is the current min-value of ith hash func.
 	h1   HashFunc
 	h2   HashFunc
-}
 
-// New MinHash instance.  It is an alias for NewMinHash.
-func New(h1, h2 HashFunc, size int) *MinHash {
-	return NewMinHash(spooky.Hash64, farm.Hash64, size)
+	// family and scratch are set only by NewMinHashWithFamily; h1/h2
+	// are left nil in that case.  Kept separate from h1/h2 rather than
+	// wrapping them in a LinearFamily so the legacy constructors below
+	// pay no extra indirection.
+	family  HashFamily
+	scratch []uint64
 }
 
 // NewMinHash constructs a new instance and pushes the optional elements.
@@ -34,6 +33,19 @@ func NewMinHash(h1, h2 HashFunc, size int) *MinHash {
 	return mw
 }
 
+// NewMinHashWithFamily constructs a new instance whose signature has
+// length k, using family to compute all k hash values for a pushed
+// element in one call.  This is the entry point for hash families that
+// can amortize per-element state or batch their work, such as
+// TabulationFamily.
+func NewMinHashWithFamily(family HashFamily, k int) *MinHash {
+	return &MinHash{
+		mins:    emptySetSignature(k),
+		family:  family,
+		scratch: make([]uint64, k),
+	}
+}
+
 func NewMinHashFromSignature(h1, h2 HashFunc, sig []uint64) *MinHash {
 	csig := make([]uint64, len(sig))
 	copy(csig, sig)
@@ -76,6 +88,11 @@ func (m *MinHash) IsEmpty() bool {
 
 // Copy returns a new MinHash instance with the same type and data.
 func (m *MinHash) Copy() *MinHash {
+	if m.family != nil {
+		c := NewMinHashWithFamily(m.family, len(m.mins))
+		copy(c.mins, m.mins)
+		return c
+	}
 	return NewMinHashFromSignature(m.h1, m.h2, m.Signature())
 }
 
@@ -87,10 +104,22 @@ func (m *MinHash) Push(x interface{}) {
 	m.PushBytes(toBytes(x))
 }
 
-// PushBytes updates the set's signature.  It hashes the input
-// with each function in the family and compares these values
-// with the current set of mins, replacing them as necessary.
+// PushBytes updates the set's signature.  If m was built with
+// NewMinHashWithFamily, it prefers the family's fused PushMins when
+// available, which folds hash values into the signature as they're
+// produced; otherwise it falls back to the family's batched Hash into
+// a scratch slice followed by a fold. Without a family it uses the
+// legacy h1 + i*h2 loop.
 func (m *MinHash) PushBytes(b []byte) {
+	if m.family != nil {
+		if fp, ok := m.family.(minPusher); ok {
+			fp.PushMins(b, m.mins)
+			return
+		}
+		m.family.Hash(b, m.scratch)
+		pushMinsBatch(m.mins, m.scratch)
+		return
+	}
 
 	v1 := m.h1(b)
 	v2 := m.h2(b)
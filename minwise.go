@@ -19,14 +19,25 @@ type MinWise struct {
 	minimums Signature
 	h1       HashFunc
 	h2       HashFunc
+
+	// family and scratch are set only by NewMinWiseWithFamily; h1/h2
+	// are left nil in that case.  Kept separate from h1/h2 rather than
+	// wrapping them in a LinearFamily so the legacy constructors below
+	// pay no extra indirection.
+	family  HashFamily
+	scratch []uint64
 }
 
 // NOTE MinWise constructors.
 
 // NewMinWise constructs a new instance and pushes the optional elements.
+//
+// Deprecated: use NewMinWiseWithFamily(LinearFamily{H1: h1, H2: h2}, size),
+// which is equivalent but also accepts TabulationFamily and other
+// HashFamily implementations.
 func NewMinWise(h1, h2 HashFunc, size int, elements ...interface{}) *MinWise {
 	mw := &MinWise{
-		minimums: defaultSignature(size), // running set of min values
+		minimums: emptySetSignature(size), // running set of min values
 		h1:       h1,
 		h2:       h2,
 	}
@@ -49,6 +60,19 @@ func NewMinWiseFromSignature(h1, h2 HashFunc, sig []uint64) *MinWise {
 	return &mw
 }
 
+// NewMinWiseWithFamily constructs a new instance whose signature has
+// length k, using family to compute all k hash values for a pushed
+// element in one call.  This is the entry point for hash families that
+// can amortize per-element state or batch their work, such as
+// TabulationFamily.
+func NewMinWiseWithFamily(family HashFamily, k int) *MinWise {
+	return &MinWise{
+		minimums: emptySetSignature(k),
+		family:   family,
+		scratch:  make([]uint64, k),
+	}
+}
+
 // InitStringIntMinWise creates a new MinWise instance and pushes a
 // set of integers (represented as strings).  The returned instance
 // contains the signature for the input set.
@@ -72,13 +96,30 @@ func (m *MinWise) IsEmpty() bool {
 
 // Copy returns a new MinWise instance with the same type and data.
 func (m *MinWise) Copy() *MinWise {
+	if m.family != nil {
+		c := NewMinWiseWithFamily(m.family, len(m.minimums))
+		copy(c.minimums, m.minimums)
+		return c
+	}
 	return NewMinWiseFromSignature(m.h1, m.h2, m.Signature())
 }
 
-// PushBytes updates the set's signature.  It hashes the input
-// with each function in the family and compares these values
-// with the current set of minimums, replacing them as necessary.
+// PushBytes updates the set's signature.  If m was built with
+// NewMinWiseWithFamily, it prefers the family's fused PushMins when
+// available, which folds hash values into the signature as they're
+// produced; otherwise it falls back to the family's batched Hash into
+// a scratch slice followed by a fold. Without a family it uses the
+// legacy h1 + i*h2 loop.
 func (m *MinWise) PushBytes(b []byte) {
+	if m.family != nil {
+		if fp, ok := m.family.(minPusher); ok {
+			fp.PushMins(b, m.minimums)
+			return
+		}
+		m.family.Hash(b, m.scratch)
+		pushMinsBatch(m.minimums, m.scratch)
+		return
+	}
 
 	v1 := m.h1(b)
 	v2 := m.h2(b)
@@ -87,7 +128,8 @@ func (m *MinWise) PushBytes(b []byte) {
 	for i, min := range m.minimums {
 		// Compute hi(b) for ith hash function hi
 		hb := v1 + uint64(i)*v2
-		if hb < min {
+		// Ensure 0 is never pushed.
+		if 0 < hb && hb < min {
 			m.minimums[i] = hb
 		}
 	}
@@ -111,16 +153,22 @@ func (m *MinWise) Signature() []uint64 {
 	return m.minimums
 }
 
+// Len returns the length of the signature, i.e. the number of hash
+// functions k in the instance's family.
+func (m *MinWise) Len() int {
+	return len(m.minimums)
+}
+
 // Similarity computes the similarity of two signatures represented
 // as MinWise instances.  This estimates the Jaccard index of the
 // two underlying sets.
-func (m *MinWise) Similarity(m2 MinHash) float64 {
+func (m *MinWise) Similarity(m2 Interface) float64 {
 	return MinWiseSimilarity(m.Signature(), m2.Signature())
 }
 
 // Merge combines the signatures of the second set,
 // creating the signature of their union.
-func (m *MinWise) Merge(m2 MinHash) {
+func (m *MinWise) Merge(m2 Interface) {
 
 	for i, v := range m2.Signature() {
 
@@ -165,20 +213,29 @@ func (m *MinWise) Cardinality() int {
 	return cardinality
 }
 
+// MeanExpCardinality estimates the cardinality of the set using the
+// mean-of-exponentials estimator; it currently computes the same
+// value as Cardinality, kept under this name for callers that want
+// this specific estimator regardless of what Cardinality does in the
+// future.
+func (m *MinWise) MeanExpCardinality() int {
+	return m.Cardinality()
+}
+
 // UnionCardinality estimates the cardinality of the union.
-func (m *MinWise) UnionCardinality(m2 MinHash) int {
+func (m *MinWise) UnionCardinality(m2 Interface) int {
 	union := m.Copy()
 	union.Merge(m2)
 	return union.Cardinality()
 }
 
 // IntersectionCardinality estimates the cardinality of the intersection.
-func (m *MinWise) IntersectionCardinality(m2 MinHash) int {
+func (m *MinWise) IntersectionCardinality(m2 Interface) int {
 	// Estimate size of the union.
 	u := m.UnionCardinality(m2)
 
 	// |A & B| + |A || B| = |A| +|B|
-	est := m.Cardinality() + m2.Cardinality() - u
+	est := m.Cardinality() + Cardinality(m2) - u
 	// Take absolute value.
 	if est < 0 {
 		est = 0
@@ -189,7 +246,7 @@ func (m *MinWise) IntersectionCardinality(m2 MinHash) int {
 
 // SymmetricDifferenceCardinality estimates the difference between
 // the cardinality of the union and intersection.
-func (m *MinWise) SymmetricDifferenceCardinality(m2 MinHash) int {
+func (m *MinWise) SymmetricDifferenceCardinality(m2 Interface) int {
 	est := m.UnionCardinality(m2) - m.IntersectionCardinality(m2)
 	if est < 0 {
 		est = 0
@@ -200,7 +257,7 @@ func (m *MinWise) SymmetricDifferenceCardinality(m2 MinHash) int {
 
 // LessCardinality estimates the cardinality of the left set minus
 // the right set. This operator is not symmetric.
-func (m *MinWise) LessCardinality(m2 MinHash) int {
+func (m *MinWise) LessCardinality(m2 Interface) int {
 	est := m.Cardinality() - m.IntersectionCardinality(m2)
 	if est < 0 {
 		est = 0
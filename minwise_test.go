@@ -5,17 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-
-	"github.com/dgryski/go-farm"
-	"github.com/dgryski/go-spooky"
 )
 
-var h1 = farm.Hash64
-var h2 = spooky.Hash64
-
 // Two signatures.
 
-func makeSigOfInts() *MinWise {
+func makeMinWiseSigOfInts() *MinWise {
 	var sig = NewMinWise(h1, h2, 400)
 	for i := 0; i <= 10000; i++ {
 		sig.Push(i)
@@ -23,7 +17,7 @@ func makeSigOfInts() *MinWise {
 	return sig
 }
 
-func makeSigOfEvens() *MinWise {
+func makeMinWiseSigOfEvens() *MinWise {
 	var sig = NewMinWise(h1, h2, 400)
 	for i := 0; i <= 10000; i++ {
 		if i%2 == 0 {
@@ -33,7 +27,7 @@ func makeSigOfEvens() *MinWise {
 	return sig
 }
 
-func makeSigOfOdds() *MinWise {
+func makeMinWiseSigOfOdds() *MinWise {
 	var sig = NewMinWise(h1, h2, 400)
 	for i := 0; i <= 10000; i++ {
 		if i%2 == 1 {
@@ -57,11 +51,11 @@ func newDummyMinWise(sig []uint64) *MinWise {
 }
 
 func TestLen(t *testing.T) {
-	s := makeSigOfInts()
+	s := makeMinWiseSigOfInts()
 	assert.Equal(t, 400, s.Len())
 }
 
-func TestPush(t *testing.T) {
+func TestMinWisePush(t *testing.T) {
 	// Test that 0 values are never pushed.
 	h := func(bs []byte) uint64 { return 0 }
 	s := NewMinWise(h, h, 2)
@@ -70,11 +64,11 @@ func TestPush(t *testing.T) {
 	assert.True(t, s.IsEmpty())
 }
 
-func TestCardinality(t *testing.T) {
+func TestMinWiseCardinality(t *testing.T) {
 
-	sigInts := makeSigOfInts()   // I
-	sigEvens := makeSigOfEvens() // E
-	sigOdds := makeSigOfOdds()   // O
+	sigInts := makeMinWiseSigOfInts()   // I
+	sigEvens := makeMinWiseSigOfEvens() // E
+	sigOdds := makeMinWiseSigOfOdds()   // O
 
 	// Empty signature should have cardinality 0.
 	assert.Equal(t, 0, NewMinWise(h1, h2, 400).Cardinality())
@@ -96,14 +90,14 @@ func TestCardinality(t *testing.T) {
 
 }
 
-func TestCopy(t *testing.T) {
-	c := makeSigOfEvens().Copy()
-	odds := makeSigOfOdds()
+func TestMinWiseCopy(t *testing.T) {
+	c := makeMinWiseSigOfEvens().Copy()
+	odds := makeMinWiseSigOfOdds()
 	c.Merge(odds)
 	log.Println("Cardinality of Evens Copy && Odds:", c.Cardinality())
 }
 
-func TestIsEmpty(t *testing.T) {
+func TestMinWiseIsEmpty(t *testing.T) {
 	var testCases = []*MinWise{
 		newDummyMinWise(nil),
 	}
@@ -113,7 +107,7 @@ func TestIsEmpty(t *testing.T) {
 	}
 }
 
-func TestSimilarity(t *testing.T) {
+func TestMinWiseSimilarity(t *testing.T) {
 	var testCases = []struct {
 		s1  *MinWise
 		s2  *MinWise
@@ -0,0 +1,160 @@
+package minhash
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SuperMinHash implements Ertl's SuperMinHash algorithm, also known as
+// one-permutation hashing with rotation: rather than drawing k
+// independent hash values per pushed element as MinWise/MinHash do, it
+// draws a single random permutation of the k output slots per element
+// and assigns each slot the position at which the permutation visits
+// it (plus a random fractional offset). Because that sweep touches
+// every slot exactly once, all k slots hold a finite value after the
+// very first Push, unlike the classic scheme where most slots stay at
+// infinity until enough elements have been seen. This removes the
+// small-cardinality "empty slot" bias and gives Ertl's stated variance
+// reduction over classic min-wise hashing for the same k.
+//
+// Slot i's value is a float64 in [0, k), initialized to k to mean
+// "unclaimed". Signature encodes each value as its raw IEEE-754 bit
+// pattern so the package's equal-slot similarity() works unchanged:
+// two SuperMinHash instances built from the same HashFunc agree on a
+// slot's bits if and only if the same element claimed it with the
+// same value.
+type SuperMinHash struct {
+	h     HashFunc
+	vals  []float64
+	perm  []int
+	seen  []int64
+	token int64
+}
+
+// NewSuperMinHash constructs a new instance with k signature slots,
+// using h to derive each pushed element's permutation.
+func NewSuperMinHash(h HashFunc, k int) *SuperMinHash {
+	vals := make([]float64, k)
+	for i := range vals {
+		vals[i] = float64(k)
+	}
+	perm := make([]int, k)
+	for i := range perm {
+		perm[i] = i
+	}
+	seen := make([]int64, k)
+	for i := range seen {
+		seen[i] = -1
+	}
+	return &SuperMinHash{
+		h:    h,
+		vals: vals,
+		perm: perm,
+		seen: seen,
+	}
+}
+
+// Push deals with generic data by handling byte conversion.
+func (m *SuperMinHash) Push(x interface{}) {
+	m.PushBytes(toBytes(x))
+}
+
+// PushBytes updates the signature with b. It seeds a PRNG stream from
+// h(b) and walks a partial Fisher-Yates shuffle of the k slots,
+// lazily reusing m.perm across pushes (m.seen's per-push token marks
+// which entries are valid for the current call rather than paying to
+// reinitialize the whole array every time), updating each visited
+// slot with the smaller of its current value and this element's
+// candidate rank.
+//
+// Ertl's original algorithm also shrinks an active-slot threshold `a`
+// as the maximum claimed rank drops, letting later pushes skip slots
+// that can no longer improve. This implementation always walks all k
+// slots, which is simpler and still correct: a slot that can no
+// longer improve just fails the "less than" comparison below and is
+// left untouched, so the only cost of omitting the threshold is the
+// speedup it would have bought on long streams.
+func (m *SuperMinHash) PushBytes(b []byte) {
+	m.token++
+	rng := rand.New(rand.NewSource(int64(m.h(b))))
+
+	k := len(m.vals)
+	for i := 0; i < k; i++ {
+		j := i + rng.Intn(k-i)
+
+		if m.seen[i] != m.token {
+			m.seen[i] = m.token
+			m.perm[i] = i
+		}
+		if m.seen[j] != m.token {
+			m.seen[j] = m.token
+			m.perm[j] = j
+		}
+		m.perm[i], m.perm[j] = m.perm[j], m.perm[i]
+
+		r := float64(i) + rng.Float64()
+		if r < m.vals[m.perm[i]] {
+			m.vals[m.perm[i]] = r
+		}
+	}
+}
+
+// Signature returns the current signature, encoding each slot's
+// float64 rank as its raw bit pattern.
+func (m *SuperMinHash) Signature() []uint64 {
+	sig := make([]uint64, len(m.vals))
+	for i, v := range m.vals {
+		sig[i] = math.Float64bits(v)
+	}
+	return sig
+}
+
+// Similarity estimates the Jaccard index of the two underlying sets
+// from the fraction of slots whose ranks agree.
+func (m *SuperMinHash) Similarity(m2 Interface) float64 {
+	return Similarity(m, m2)
+}
+
+// Merge combines m2's signature into m's, keeping the smaller rank in
+// each slot, as MinHash.Merge and MinWise.Merge do for their own
+// signatures. m and m2 must have been built with the same HashFunc and
+// k for the result to be meaningful.
+func (m *SuperMinHash) Merge(m2 Interface) {
+	for i, bits := range m2.Signature() {
+		v := math.Float64frombits(bits)
+		if v < m.vals[i] {
+			m.vals[i] = v
+		}
+	}
+}
+
+// Cardinality estimates |A| from the mean claimed rank across slots.
+// For a random permutation sweep over k slots and n elements, the
+// expected minimum rank claimed in a slot is k/(n+1), the classic
+// order-statistic result for the minimum of n uniform draws over a
+// range of k; inverting that relation gives the estimator below. Both
+// the empty set (every slot still at its initial value of k, i.e.
+// mean k, giving an estimate of 0) and a heavily saturated signature
+// (mean near 0) fall out of the same formula; only a mean of exactly 0,
+// which would divide by zero, is special-cased to MaxInt.
+func (m *SuperMinHash) Cardinality() int {
+	k := len(m.vals)
+	if k == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range m.vals {
+		sum += v
+	}
+	mean := sum / float64(k)
+	if mean == 0 {
+		return MaxInt
+	}
+
+	est := float64(k)/mean - 1
+	if est < 0 {
+		est = 0
+	}
+	return int(est)
+}
@@ -0,0 +1,120 @@
+package minhash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuperMinHashNoEmptySlots(t *testing.T) {
+	// A single push should claim every slot, unlike MinWise/MinHash
+	// where most slots stay at infinity until many elements are seen.
+	s := NewSuperMinHash(h1, 64)
+	s.Push(1)
+
+	for _, v := range s.Signature() {
+		assert.NotEqual(t, math.Float64bits(64), v)
+	}
+}
+
+func TestSuperMinHashSimilarity(t *testing.T) {
+	a := NewSuperMinHash(h1, 256)
+	b := NewSuperMinHash(h1, 256)
+
+	for i := 0; i <= 1000; i++ {
+		a.Push(i)
+		if i%2 == 0 {
+			b.Push(i)
+		}
+	}
+
+	sim := a.Similarity(b)
+	assert.True(t, sim > 0.3 && sim < 0.7, "unexpected similarity for half-overlapping sets: %f", sim)
+}
+
+func TestSuperMinHashMerge(t *testing.T) {
+	evens := NewSuperMinHash(h1, 128)
+	odds := NewSuperMinHash(h1, 128)
+	for i := 0; i <= 200; i++ {
+		if i%2 == 0 {
+			evens.Push(i)
+		} else {
+			odds.Push(i)
+		}
+	}
+
+	union := NewSuperMinHash(h1, 128)
+	for i := 0; i <= 200; i++ {
+		union.Push(i)
+	}
+
+	merged := NewSuperMinHash(h1, 128)
+	for i := 0; i <= 200; i++ {
+		if i%2 == 0 {
+			merged.Push(i)
+		}
+	}
+	merged.Merge(odds)
+
+	assert.Equal(t, union.Signature(), merged.Signature())
+}
+
+func TestSuperMinHashCardinalityEmpty(t *testing.T) {
+	assert.Equal(t, 0, NewSuperMinHash(h1, 64).Cardinality())
+}
+
+// TestSuperMinHashMSEvsMinWise compares SuperMinHash's similarity
+// estimation error against MinWise's, both with k=256, over repeated
+// trials of two overlapping sets of size |A|=50. SuperMinHash's
+// one-permutation-with-rotation scheme has strictly lower variance
+// than MinWise's k independent hash draws, so its MSE against the true
+// Jaccard similarity should not exceed MinWise's.
+func TestSuperMinHashMSEvsMinWise(t *testing.T) {
+	const (
+		k       = 256
+		setSize = 50
+		overlap = 25 // |A ^ B| = 25, so true Jaccard = 25/75
+		trials  = 200
+	)
+	trueSim := float64(overlap) / float64(2*setSize-overlap)
+
+	var superSE, minwiseSE float64
+	for trial := 0; trial < trials; trial++ {
+		super1 := NewSuperMinHash(h1, k)
+		super2 := NewSuperMinHash(h1, k)
+		mw1 := NewMinWise(h1, h2, k)
+		mw2 := NewMinWise(h1, h2, k)
+
+		base := trial * setSize * 4
+		for i := 0; i < overlap; i++ {
+			x := base + i
+			super1.Push(x)
+			super2.Push(x)
+			mw1.Push(x)
+			mw2.Push(x)
+		}
+		for i := overlap; i < setSize; i++ {
+			x := base + i
+			super1.Push(x)
+			mw1.Push(x)
+		}
+		for i := overlap; i < setSize; i++ {
+			x := base + setSize + i
+			super2.Push(x)
+			mw2.Push(x)
+		}
+
+		superErr := super1.Similarity(super2) - trueSim
+		minwiseErr := Similarity(mw1, mw2) - trueSim
+		superSE += superErr * superErr
+		minwiseSE += minwiseErr * minwiseErr
+	}
+
+	superMSE := superSE / trials
+	minwiseMSE := minwiseSE / trials
+	t.Logf("SuperMinHash MSE=%.6f MinWise MSE=%.6f", superMSE, minwiseMSE)
+
+	assert.True(t, superMSE <= minwiseMSE,
+		"expected SuperMinHash MSE (%.6f) <= MinWise MSE (%.6f)", superMSE, minwiseMSE)
+}
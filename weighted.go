@@ -0,0 +1,130 @@
+package minhash
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// WeightedMinHash estimates the generalized Jaccard similarity
+// sum(min(xi,yi)) / sum(max(xi,yi)) of two non-negative weighted
+// vectors (e.g. TF-style bag-of-words counts), rather than the
+// unweighted Jaccard similarity MinHash/MinWise estimate over plain
+// sets.  It implements Ioffe's Consistent Weighted Sampling: for each
+// of k independent "samplers" and each pushed feature, it draws three
+// values deterministically from the feature id and sampler index and
+// keeps the feature achieving the smallest score for that sampler.
+// Equal-slot counting over the resulting signatures (via Similarity,
+// or the package-level similarity used by Similarity) then estimates
+// the weighted Jaccard index just as it does for MinHash.
+type WeightedMinHash struct {
+	h     HashFunc
+	best  []uint64
+	bestA []float64
+}
+
+// NewWeightedMinHash constructs a WeightedMinHash with k samplers,
+// using h to derive the deterministic per-sampler randomness.
+func NewWeightedMinHash(h HashFunc, k int) *WeightedMinHash {
+	bestA := make([]float64, k)
+	for i := range bestA {
+		bestA[i] = math.Inf(1)
+	}
+	return &WeightedMinHash{
+		h:     h,
+		best:  make([]uint64, k),
+		bestA: bestA,
+	}
+}
+
+// PushWeighted updates the signature with a feature and its
+// non-negative weight.  Features with weight <= 0 are ignored, as they
+// contribute nothing to sum(min(xi,yi))/sum(max(xi,yi)).
+func (m *WeightedMinHash) PushWeighted(feature uint64, weight float64) {
+	if weight <= 0 {
+		return
+	}
+
+	logW := math.Log(weight)
+	for s := range m.bestA {
+		r, c, beta := m.sample(feature, uint64(s))
+
+		t := math.Floor(logW/r + beta)
+		y := math.Exp(r * (t - beta))
+		a := c / (y * math.Exp(r))
+
+		if a < m.bestA[s] {
+			m.bestA[s] = a
+			m.best[s] = packSample(feature, t)
+		}
+	}
+}
+
+// sample draws the (r, c, beta) triple for the given feature and
+// sampler index, deterministically from m.h. Ioffe's consistent
+// weighted sampling requires r and c to be drawn from Gamma(2,1), not
+// Uniform(0,1): a Gamma(2,1) variate can be produced as -log(u1*u2)
+// for independent u1, u2 ~ Uniform(0,1), so each of r and c consumes
+// two salted hashes.
+func (m *WeightedMinHash) sample(feature, sampler uint64) (r, c, beta float64) {
+	r = m.gamma21(feature, sampler, 0)
+	c = m.gamma21(feature, sampler, 2)
+	beta = m.unit(feature, sampler, 4)
+
+	// r and c appear as divisors; keep them off zero.
+	if r == 0 {
+		r = minSampleValue
+	}
+	if c == 0 {
+		c = minSampleValue
+	}
+	return r, c, beta
+}
+
+// gamma21 draws a Gamma(2,1)-distributed value from two salted unit
+// draws starting at salt, via -log(u1*u2).
+func (m *WeightedMinHash) gamma21(feature, sampler, salt uint64) float64 {
+	u1 := m.unit(feature, sampler, salt)
+	u2 := m.unit(feature, sampler, salt+1)
+	if u1 == 0 {
+		u1 = minSampleValue
+	}
+	if u2 == 0 {
+		u2 = minSampleValue
+	}
+	return -math.Log(u1 * u2)
+}
+
+// minSampleValue substitutes for a sampled value of exactly zero,
+// which would otherwise divide by zero below.
+const minSampleValue = 1e-12
+
+// unit hashes (feature, sampler, salt) through m.h and maps the result
+// to a value in [0, 1).
+func (m *WeightedMinHash) unit(feature, sampler, salt uint64) float64 {
+	var b [24]byte
+	binary.LittleEndian.PutUint64(b[0:8], feature)
+	binary.LittleEndian.PutUint64(b[8:16], sampler)
+	binary.LittleEndian.PutUint64(b[16:24], salt)
+
+	h := m.h(b[:])
+	// Use the top 53 bits so the result is uniform over the 53 bits of
+	// a float64 mantissa.
+	return float64(h>>11) / float64(uint64(1)<<53)
+}
+
+// packSample packs a sample's feature id and truncated t-value into a
+// single uint64: the high 32 bits hold feature, the low 32 bits hold t.
+func packSample(feature uint64, t float64) uint64 {
+	return (feature&0xffffffff)<<32 | uint64(int32(t))&0xffffffff
+}
+
+// Signature returns the k packed (feature, t) samples.
+func (m *WeightedMinHash) Signature() []uint64 {
+	return m.best
+}
+
+// Similarity estimates the generalized Jaccard similarity between m
+// and m2 by counting the fraction of samplers whose samples agree.
+func (m *WeightedMinHash) Similarity(m2 Interface) float64 {
+	return Similarity(m, m2)
+}
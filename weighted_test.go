@@ -0,0 +1,122 @@
+package minhash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bagOfWords maps a feature id to its weight.
+type bagOfWords map[uint64]float64
+
+func sigOfWeighted(k int, bag bagOfWords) *WeightedMinHash {
+	m := NewWeightedMinHash(h1, k)
+	for feature, weight := range bag {
+		m.PushWeighted(feature, weight)
+	}
+	return m
+}
+
+// trueWeightedJaccard computes sum(min(xi,yi))/sum(max(xi,yi)) exactly.
+func trueWeightedJaccard(a, b bagOfWords) float64 {
+	var mins, maxs float64
+	seen := make(map[uint64]bool, len(a)+len(b))
+
+	for f, wa := range a {
+		wb := b[f]
+		mins += math.Min(wa, wb)
+		maxs += math.Max(wa, wb)
+		seen[f] = true
+	}
+	for f, wb := range b {
+		if seen[f] {
+			continue
+		}
+		mins += math.Min(0, wb)
+		maxs += math.Max(0, wb)
+	}
+
+	return mins / maxs
+}
+
+func TestWeightedMinHashIdentical(t *testing.T) {
+	bag := bagOfWords{1: 3, 2: 1, 3: 5, 4: 2}
+	a := sigOfWeighted(256, bag)
+	b := sigOfWeighted(256, bag)
+	assert.Equal(t, 1.0, a.Similarity(b))
+}
+
+func TestWeightedMinHashDisjoint(t *testing.T) {
+	a := sigOfWeighted(256, bagOfWords{1: 1, 2: 1})
+	b := sigOfWeighted(256, bagOfWords{100: 1, 200: 1})
+	assert.Equal(t, 0.0, a.Similarity(b))
+}
+
+// TestWeightedMinHashEstimatorVariance checks that the estimator's
+// error, averaged over repeated trials, shrinks like k^{-1/2} as k
+// grows -- it does not need to be tight, just trending the right way.
+func TestWeightedMinHashEstimatorVariance(t *testing.T) {
+	bagA := bagOfWords{1: 4, 2: 1, 3: 7, 4: 2, 5: 1}
+	bagB := bagOfWords{1: 2, 2: 1, 3: 3, 4: 6, 6: 5}
+	truth := trueWeightedJaccard(bagA, bagB)
+
+	const trials = 40
+	errAt := func(k int) float64 {
+		var sumSq float64
+		for trial := 0; trial < trials; trial++ {
+			h := func(seed int) HashFunc {
+				return func(b []byte) uint64 {
+					return farmSeeded(uint64(seed), b)
+				}
+			}(trial)
+
+			a := NewWeightedMinHash(h, k)
+			for f, w := range bagA {
+				a.PushWeighted(f, w)
+			}
+			b := NewWeightedMinHash(h, k)
+			for f, w := range bagB {
+				b.PushWeighted(f, w)
+			}
+
+			d := a.Similarity(b) - truth
+			sumSq += d * d
+		}
+		return math.Sqrt(sumSq / trials)
+	}
+
+	errSmallK := errAt(16)
+	errLargeK := errAt(256)
+
+	// k^{-1/2} scaling predicts a 4x drop from k=16 to k=256; require at
+	// least half of that.
+	assert.True(t, errLargeK <= errSmallK/2, "expected error at k=256 (%f) to be at most half the error at k=16 (%f)", errLargeK, errSmallK)
+
+	h := func(b []byte) uint64 { return farmSeeded(1, b) }
+	a := sigOfWeighted2(256, h, bagA)
+	b := sigOfWeighted2(256, h, bagB)
+	assert.InDelta(t, truth, a.Similarity(b), 0.1, "estimate at k=256 should be close to the true weighted Jaccard %f", truth)
+}
+
+// sigOfWeighted2 is like sigOfWeighted but takes an explicit hash
+// function so tests can reuse the same seed across two signatures.
+func sigOfWeighted2(k int, h HashFunc, bag bagOfWords) *WeightedMinHash {
+	m := NewWeightedMinHash(h, k)
+	for feature, weight := range bag {
+		m.PushWeighted(feature, weight)
+	}
+	return m
+}
+
+// farmSeeded folds a trial seed into the byte slice before delegating
+// to the package's farm hash, giving independent but reproducible
+// hash families across trials.
+func farmSeeded(seed uint64, b []byte) uint64 {
+	salted := make([]byte, len(b)+8)
+	copy(salted, b)
+	for i := 0; i < 8; i++ {
+		salted[len(b)+i] = byte(seed >> (8 * i))
+	}
+	return h1(salted)
+}